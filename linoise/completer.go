@@ -0,0 +1,159 @@
+// Copyright 2010  The "go-linoise" Authors
+//
+// Use of this source code is governed by the Simplified BSD License
+// that can be found in the LICENSE file.
+//
+// This software is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES
+// OR CONDITIONS OF ANY KIND, either express or implied. See the License
+// for more details.
+
+package linoise
+
+import (
+	"fmt"
+	"strings"
+)
+
+
+// A Completer returns completion candidates for the runes before 'pos'
+// in 'line'. 'length' is how many trailing runes of 'line' the first
+// candidate replaces.
+type Completer interface {
+	Do(line []int, pos int) (newLine [][]int, length int)
+}
+
+
+// === PrefixCompleter
+// ===
+
+// A PrefixCompleter completes against a fixed set of command and
+// subcommand strings, organized as a trie.
+type PrefixCompleter struct {
+	name     string
+	children []*PrefixCompleter
+}
+
+// Builds a PrefixCompleter whose top-level candidates are 'names', each
+// of which may itself have subcommands attached with 'AddChild'.
+func NewPrefixCompleter(names ...string) *PrefixCompleter {
+	p := &PrefixCompleter{}
+	for _, n := range names {
+		p.children = append(p.children, &PrefixCompleter{name: n})
+	}
+	return p
+}
+
+// Attaches the children of 'child' as subcommands of the completer named
+// 'name'; the name of 'child' itself is not used. Nothing is attached if
+// no top-level completer has that name.
+func (p *PrefixCompleter) AddChild(name string, child *PrefixCompleter) {
+	for _, c := range p.children {
+		if c.name == name {
+			c.children = append(c.children, child.children...)
+			return
+		}
+	}
+}
+
+func (p *PrefixCompleter) Do(line []int, pos int) ([][]int, int) {
+	typed := string(line[:pos])
+	fields := strings.Fields(typed)
+
+	// A trailing space means the last field was already completed and
+	// the user has moved on to typing (nothing of) the next segment, so
+	// it is walked into the trie instead of being treated as a prefix.
+	trailingSpace := strings.HasSuffix(typed, " ") || strings.HasSuffix(typed, "\t")
+
+	walk := len(fields)
+	if !trailingSpace && walk > 0 {
+		walk--
+	}
+
+	node := p
+	for i := 0; i < walk; i++ {
+		next := matchChild(node, fields[i])
+		if next == nil {
+			return nil, 0
+		}
+		node = next
+	}
+
+	prefix := ""
+	if !trailingSpace && len(fields) > 0 {
+		prefix = fields[len(fields)-1]
+	}
+
+	var out [][]int
+	for _, c := range node.children {
+		if strings.HasPrefix(c.name, prefix) {
+			out = append(out, []int(c.name))
+		}
+	}
+	return out, len([]int(prefix))
+}
+
+func matchChild(p *PrefixCompleter, name string) *PrefixCompleter {
+	for _, c := range p.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+// ===
+
+
+// === SegmentCompleter
+// ===
+
+// A SegmentCompleter completes path-like tokens, split by 'Sep', against
+// a static set of segment choices per depth.
+type SegmentCompleter struct {
+	Sep      string
+	Segments [][]string
+}
+
+func (s *SegmentCompleter) Do(line []int, pos int) ([][]int, int) {
+	typed := string(line[:pos])
+	parts := strings.Split(typed, s.Sep)
+	depth := len(parts) - 1
+	if depth >= len(s.Segments) {
+		return nil, 0
+	}
+
+	prefix := parts[len(parts)-1]
+	var out [][]int
+	for _, seg := range s.Segments[depth] {
+		if strings.HasPrefix(seg, prefix) {
+			out = append(out, []int(seg))
+		}
+	}
+	return out, len([]int(prefix))
+}
+// ===
+
+
+// Runs 'c' against the current buffer at 'pos'; if there is exactly one
+// candidate it is returned to be inserted in place of the last 'length'
+// runes, otherwise the candidates are printed in columns and 0 is
+// returned.
+func (l *Line) complete(c Completer) (insert []int, length int) {
+	candidates, length := c.Do(l.buf[:l.pos], l.pos)
+
+	if len(candidates) == 1 {
+		return candidates[0], length
+	}
+	if len(candidates) > 1 {
+		output.WriteString("\r\n")
+		printColumns(candidates)
+		output.WriteString(l.prompt + string(l.buf))
+	}
+	return nil, 0
+}
+
+func printColumns(candidates [][]int) {
+	for _, c := range candidates {
+		fmt.Fprintf(output, "%s  ", string(c))
+	}
+	output.WriteString("\r\n")
+}
@@ -0,0 +1,106 @@
+// Copyright 2010  The "go-linoise" Authors
+//
+// Use of this source code is governed by the Simplified BSD License
+// that can be found in the LICENSE file.
+//
+// This software is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES
+// OR CONDITIONS OF ANY KIND, either express or implied. See the License
+// for more details.
+
+package linoise
+
+import "testing"
+
+func candidateNames(candidates [][]int) map[string]bool {
+	names := make(map[string]bool)
+	for _, c := range candidates {
+		names[string(c)] = true
+	}
+	return names
+}
+
+func TestPrefixCompleterTopLevel(t *testing.T) {
+	p := NewPrefixCompleter("connect", "disconnect")
+
+	candidates, length := p.Do([]int("c"), 1)
+	if length != 1 {
+		t.Errorf("length = %d, want 1", length)
+	}
+	if names := candidateNames(candidates); !names["connect"] || len(names) != 1 {
+		t.Errorf("candidates = %v, want just \"connect\"", names)
+	}
+}
+
+func TestPrefixCompleterWalksIntoSubcommands(t *testing.T) {
+	p := NewPrefixCompleter("remote")
+	p.AddChild("remote", &PrefixCompleter{children: []*PrefixCompleter{
+		{name: "add"}, {name: "remove"},
+	}})
+
+	// A trailing space after a completed top-level name walks into its
+	// children instead of treating "remote" itself as the prefix.
+	candidates, length := p.Do([]int("remote "), len("remote "))
+	if length != 0 {
+		t.Errorf("length = %d, want 0 after a trailing space", length)
+	}
+	if names := candidateNames(candidates); !names["add"] || !names["remove"] {
+		t.Errorf("candidates = %v, want \"add\" and \"remove\"", names)
+	}
+
+	// No trailing space: still completing the top-level name itself.
+	candidates, length = p.Do([]int("remote"), len("remote"))
+	if length != len("remote") {
+		t.Errorf("length = %d, want %d", length, len("remote"))
+	}
+	if names := candidateNames(candidates); !names["remote"] || len(names) != 1 {
+		t.Errorf("candidates = %v, want just \"remote\"", names)
+	}
+}
+
+func TestPrefixCompleterUnknownCommand(t *testing.T) {
+	p := NewPrefixCompleter("connect")
+	candidates, length := p.Do([]int("bogus sub"), len("bogus sub"))
+	if candidates != nil || length != 0 {
+		t.Errorf("Do on an unknown command = (%v, %d), want (nil, 0)", candidates, length)
+	}
+}
+
+func TestPrefixCompleterAddChildMergesExisting(t *testing.T) {
+	p := NewPrefixCompleter("remote")
+	p.AddChild("remote", &PrefixCompleter{children: []*PrefixCompleter{{name: "add"}}})
+	p.AddChild("remote", &PrefixCompleter{children: []*PrefixCompleter{{name: "remove"}}})
+
+	node := matchChild(p, "remote")
+	if len(node.children) != 2 {
+		t.Fatalf("after two AddChild calls, children = %v, want 2 entries", node.children)
+	}
+}
+
+func TestSegmentCompleter(t *testing.T) {
+	s := &SegmentCompleter{Sep: "/", Segments: [][]string{
+		{"usr", "var"},
+		{"local", "log"},
+	}}
+
+	candidates, length := s.Do([]int("us"), 2)
+	if length != 2 {
+		t.Errorf("length = %d, want 2", length)
+	}
+	if names := candidateNames(candidates); !names["usr"] || len(names) != 1 {
+		t.Errorf("candidates = %v, want just \"usr\"", names)
+	}
+
+	candidates, length = s.Do([]int("usr/lo"), len("usr/lo"))
+	if length != 2 {
+		t.Errorf("length = %d, want 2", length)
+	}
+	if names := candidateNames(candidates); !names["local"] || !names["log"] {
+		t.Errorf("candidates = %v, want \"local\" and \"log\"", names)
+	}
+
+	// Past the configured depth, there is nothing left to complete.
+	candidates, length = s.Do([]int("usr/local/x"), len("usr/local/x"))
+	if candidates != nil || length != 0 {
+		t.Errorf("Do past the configured depth = (%v, %d), want (nil, 0)", candidates, length)
+	}
+}
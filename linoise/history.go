@@ -0,0 +1,108 @@
+// Copyright 2010  The "go-linoise" Authors
+//
+// Use of this source code is governed by the Simplified BSD License
+// that can be found in the LICENSE file.
+//
+// This software is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES
+// OR CONDITIONS OF ANY KIND, either express or implied. See the License
+// for more details.
+
+package linoise
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+
+// A History stores previously entered lines, oldest first, trimmed to
+// at most 'max' entries. It is safe to pass a nil *History wherever one
+// is expected; it behaves as an empty, discard-on-add history.
+type History struct {
+	path    string
+	max     int
+	entries []string
+}
+
+// Gets an in-memory history that keeps at most 'max' entries.
+func NewMemHistory(max int) *History {
+	return &History{max: max}
+}
+
+// Gets a history backed by the file at 'path', loading any entries
+// already there and appending new ones as they are added. It keeps at
+// most 'max' entries, discarding the oldest ones first.
+func NewFileHistory(path string, max int) (*History, os.Error) {
+	h := &History{path: path, max: max}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if pe, ok := err.(*os.PathError); ok && pe.Error == os.ENOENT {
+			return h, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewReader(f)
+	for {
+		line, err := scanner.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if line != "" {
+			h.entries = append(h.entries, line)
+		}
+		if err != nil {
+			break
+		}
+	}
+	h.trim()
+	return h, nil
+}
+
+// Appends 'line' to the history, trimming the oldest entry if it is now
+// over capacity, and persisting to disk when backed by a file.
+func (h *History) Add(line string) {
+	if h == nil {
+		return
+	}
+
+	h.entries = append(h.entries, line)
+	h.trim()
+
+	if h.path != "" {
+		f, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err == nil {
+			f.WriteString(line + "\n")
+			f.Close()
+		}
+	}
+}
+
+func (h *History) trim() {
+	if h.max > 0 && len(h.entries) > h.max {
+		h.entries = h.entries[len(h.entries)-h.max:]
+	}
+}
+
+// Returns the stored entries, oldest first.
+func (h *History) Entries() []string {
+	if h == nil {
+		return nil
+	}
+	return h.entries
+}
+
+// Searches backwards from 'from' (exclusive) for the most recent entry
+// containing 'substr'. It returns -1 if there is no match.
+func (h *History) searchBackward(from int, substr string) int {
+	if h == nil || substr == "" {
+		return -1
+	}
+	for i := from - 1; i >= 0; i-- {
+		if strings.Contains(h.entries[i], substr) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,90 @@
+// Copyright 2010  The "go-linoise" Authors
+//
+// Use of this source code is governed by the Simplified BSD License
+// that can be found in the LICENSE file.
+//
+// This software is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES
+// OR CONDITIONS OF ANY KIND, either express or implied. See the License
+// for more details.
+
+package linoise
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMemHistoryAddAndTrim(t *testing.T) {
+	h := NewMemHistory(2)
+	h.Add("one")
+	h.Add("two")
+	h.Add("three")
+
+	got := h.Entries()
+	want := []string{"two", "three"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Entries() = %v, want %v", got, want)
+	}
+}
+
+func TestNilHistory(t *testing.T) {
+	var h *History
+	h.Add("ignored") // must not panic
+	if got := h.Entries(); got != nil {
+		t.Errorf("Entries() on a nil History = %v, want nil", got)
+	}
+}
+
+func TestFileHistoryLoadAndPersist(t *testing.T) {
+	path := os.TempDir() + "/linoise-history-test"
+	os.Remove(path)
+	defer os.Remove(path)
+
+	h, err := NewFileHistory(path, 10)
+	if err != nil {
+		t.Fatalf("NewFileHistory on a missing file: %s", err)
+	}
+	if len(h.Entries()) != 0 {
+		t.Fatalf("Entries() on a fresh file history = %v, want none", h.Entries())
+	}
+
+	h.Add("first")
+	h.Add("second")
+
+	reloaded, err := NewFileHistory(path, 10)
+	if err != nil {
+		t.Fatalf("NewFileHistory reloading: %s", err)
+	}
+
+	got := reloaded.Entries()
+	if len(got) != 2 || got[0] != "first" || got[1] != "second" {
+		t.Errorf("reloaded Entries() = %v, want [first second]", got)
+	}
+}
+
+func TestSearchBackward(t *testing.T) {
+	h := NewMemHistory(10)
+	h.Add("connect host-a")
+	h.Add("disconnect")
+	h.Add("connect host-b")
+
+	if i := h.searchBackward(len(h.entries), "connect"); i != 2 {
+		t.Errorf("searchBackward(len, \"connect\") = %d, want 2", i)
+	}
+
+	// The match at 2 continues the search strictly before it, the same
+	// way Ctrl-R cycles to an older match; this is the exact call shape
+	// 'reverseSearch' makes, guarding the off-by-one fixed in fab2141.
+	// "disconnect" at 1 contains "connect" too, so it is the next match.
+	if i := h.searchBackward(2, "connect"); i != 1 {
+		t.Errorf("searchBackward(2, \"connect\") = %d, want 1", i)
+	}
+
+	if i := h.searchBackward(len(h.entries), "missing"); i != -1 {
+		t.Errorf("searchBackward for a missing substring = %d, want -1", i)
+	}
+
+	if i := h.searchBackward(len(h.entries), ""); i != -1 {
+		t.Errorf("searchBackward with an empty pattern = %d, want -1", i)
+	}
+}
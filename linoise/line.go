@@ -0,0 +1,228 @@
+// Copyright 2010  The "go-linoise" Authors
+//
+// Use of this source code is governed by the Simplified BSD License
+// that can be found in the LICENSE file.
+//
+// This software is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES
+// OR CONDITIONS OF ANY KIND, either express or implied. See the License
+// for more details.
+
+package linoise
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kless/go-term/term"
+)
+
+
+// Where the output is written.
+var output = os.Stdout
+
+// Returned by 'Line.Read' when the user presses Ctrl-D on an empty line.
+var ErrCtrlD = os.NewError("linoise: EOF (Ctrl-D)")
+
+
+// === Type
+// ===
+
+// A Line handles a single line of raw-mode terminal input, showing a
+// prompt and optionally recording it in, and searching it with Ctrl-R
+// from, an input history.
+type Line struct {
+	prompt    string
+	history   *History
+	completer Completer
+	buf       []int
+	pos       int
+}
+
+// Gets a line type that shows the given prompt and, if not nil, keeps
+// track of the given history.
+func NewLinePrompt(prompt string, history *History) *Line {
+	return &Line{prompt: prompt, history: history}
+}
+
+// Gets a line type like 'NewLinePrompt', but invoking 'completer' when
+// the user presses Tab.
+func NewLineWithCompleter(prompt string, history *History, completer Completer) *Line {
+	return &Line{prompt: prompt, history: history, completer: completer}
+}
+// ===
+
+
+// Shows the prompt and reads a line until the user presses Return or
+// Ctrl-D. On Ctrl-D with an empty buffer, it returns 'ErrCtrlD'.
+func (l *Line) Read() (string, os.Error) {
+	if err := term.RawMode(); err != nil {
+		return "", err
+	}
+	defer term.RestoreTerm()
+
+	output.WriteString(l.prompt)
+	l.buf = l.buf[:0]
+	l.pos = 0
+
+	for {
+		r, err := readRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			output.WriteString("\r\n")
+			line := string(l.buf)
+			if l.history != nil && line != "" {
+				l.history.Add(line)
+			}
+			return line, nil
+		case 4: // Ctrl-D
+			if len(l.buf) == 0 {
+				return "", ErrCtrlD
+			}
+		case 127, 8: // Backspace
+			if l.pos > 0 {
+				l.buf = append(l.buf[:l.pos-1], l.buf[l.pos:]...)
+				l.pos--
+				l.redraw()
+			}
+		case '\t': // Tab
+			if l.completer != nil {
+				insert, length := l.complete(l.completer)
+				if insert != nil {
+					l.buf = append(l.buf[:l.pos-length], append(insert, l.buf[l.pos:]...)...)
+					l.pos += len(insert) - length
+					l.redraw()
+				}
+			}
+		case 18: // Ctrl-R
+			line, ok := l.reverseSearch()
+			if ok {
+				output.WriteString("\r\n")
+				return line, nil
+			}
+			l.redraw()
+		default:
+			l.buf = append(l.buf[:l.pos], append([]int{r}, l.buf[l.pos:]...)...)
+			l.pos++
+			l.redraw()
+		}
+	}
+	return string(l.buf), nil
+}
+
+// Redraws the current line after the prompt.
+func (l *Line) redraw() {
+	output.WriteString("\r" + l.prompt + string(l.buf) + " \r" + l.prompt)
+	for i := 0; i < l.pos; i++ {
+		output.WriteString(string(l.buf[i]))
+	}
+}
+
+// Runs an incremental reverse-search sub-prompt over 'l.history', as in
+// "(reverse-i-search)'pattern': match". Each typed rune refines the
+// search, Ctrl-R cycles to the next older match, Enter accepts the
+// match, and Ctrl-G cancels back to the line as it was. It returns the
+// accepted line and true, or "" and false if the search was cancelled.
+func (l *Line) reverseSearch() (string, bool) {
+	if l.history == nil {
+		return "", false
+	}
+
+	pattern := ""
+	match := ""
+	pos := len(l.history.entries)
+
+	render := func() {
+		fmt.Fprintf(output, "\r(reverse-i-search)'%s': %s", pattern, match)
+	}
+	render()
+
+	for {
+		r, err := readRune()
+		if err != nil {
+			return "", false
+		}
+
+		switch r {
+		case '\r', '\n':
+			return match, match != ""
+		case 7: // Ctrl-G
+			return "", false
+		case 18: // Ctrl-R: cycle to the next older match
+			if i := l.history.searchBackward(pos, pattern); i >= 0 {
+				pos = i
+				match = l.history.entries[i]
+			}
+			render()
+			continue
+		case 127, 8:
+			if len(pattern) > 0 {
+				pattern = pattern[:len(pattern)-1]
+			}
+		default:
+			pattern += string(r)
+		}
+		pos = len(l.history.entries)
+
+		if i := l.history.searchBackward(pos, pattern); i >= 0 {
+			pos = i
+			match = l.history.entries[i]
+		} else {
+			match = ""
+		}
+		render()
+	}
+	return "", false
+}
+
+// Reads a single rune from the terminal, decoding its UTF-8 encoding so
+// a multi-byte keystroke (e.g. the 'í' in the "es" locale's "sí") comes
+// back as one codepoint instead of several bogus ones.
+func readRune() (int, os.Error) {
+	var b [4]byte
+
+	if _, err := os.Stdin.Read(b[:1]); err != nil {
+		return 0, err
+	}
+
+	size := utf8RuneSize(b[0])
+	for i := 1; i < size; i++ {
+		if _, err := os.Stdin.Read(b[i : i+1]); err != nil {
+			return 0, err
+		}
+	}
+
+	return decodeRune(b[:size]), nil
+}
+
+// Returns how many bytes the UTF-8 encoding starting with the leading
+// byte 'b' occupies.
+func utf8RuneSize(b byte) int {
+	switch {
+	case b&0x80 == 0x00:
+		return 1
+	case b&0xE0 == 0xC0:
+		return 2
+	case b&0xF0 == 0xE0:
+		return 3
+	case b&0xF8 == 0xF0:
+		return 4
+	}
+	return 1 // Invalid leading byte; treat it as a single codepoint.
+}
+
+// Decodes the codepoint encoded by the UTF-8 bytes 'b'.
+func decodeRune(b []byte) int {
+	switch len(b) {
+	case 2:
+		return int(b[0]&0x1F)<<6 | int(b[1]&0x3F)
+	case 3:
+		return int(b[0]&0x0F)<<12 | int(b[1]&0x3F)<<6 | int(b[2]&0x3F)
+	case 4:
+		return int(b[0]&0x07)<<18 | int(b[1]&0x3F)<<12 | int(b[2]&0x3F)<<6 | int(b[3]&0x3F)
+	}
+	return int(b[0])
+}
@@ -0,0 +1,162 @@
+// Copyright 2010  The "go-linoise" Authors
+//
+// Use of this source code is governed by the Simplified BSD License
+// that can be found in the LICENSE file.
+//
+// This software is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES
+// OR CONDITIONS OF ANY KIND, either express or implied. See the License
+// for more details.
+
+package linoise
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+
+// A Locale carries every user-facing string that 'Question' shows, so a
+// program can translate its prompts without patching the library.
+type Locale struct {
+	TrueString  string // String to represent 'true', e.g. "y"
+	FalseString string // String to represent 'false', e.g. "n"
+
+	// Extra strings accepted as a boolean, matched case-insensitively;
+	// replaces the old package-level 'ExtraBoolString' map.
+	BoolAliases map[string]bool
+
+	ErrNotInt   string // Shown when an integer was expected
+	ErrNotFloat string // Shown when a float was expected
+	ErrNotBool  string // Shown when a boolean was expected
+
+	// Punctuation appended to a prompt: 'QuestionMark' when it already
+	// ends in '?', 'Colon' otherwise.
+	QuestionMark string
+	Colon        string
+}
+
+// Returns the boolean represented by 'str' under this locale: its own
+// 'TrueString'/'FalseString', 'strconv.Atob', or 'BoolAliases', matched
+// case-insensitively. Any other value is an error.
+func (l Locale) atob(str string) (bool, os.Error) {
+	if v, err := strconv.Atob(str); err == nil {
+		return v, nil
+	}
+
+	folded := strings.ToLower(str)
+	if folded == strings.ToLower(l.TrueString) {
+		return true, nil
+	}
+	if folded == strings.ToLower(l.FalseString) {
+		return false, nil
+	}
+
+	for alias, value := range l.BoolAliases {
+		if folded == strings.ToLower(alias) {
+			return value, nil
+		}
+	}
+
+	return false, os.NewError(l.ErrNotBool)
+}
+
+
+var locales = map[string]Locale{}
+
+// Current locale, set with 'SetLocale'; English until changed. Assigned
+// at the end of 'init', once the built-in locales are registered.
+var currentLocale Locale
+
+func init() {
+	mustRegisterLocale("en", Locale{
+		TrueString: "y", FalseString: "n",
+		BoolAliases: map[string]bool{"yes": true, "no": false},
+		ErrNotInt:   "value has to be an integer",
+		ErrNotFloat: "value has to be a float",
+		ErrNotBool:  "does not represent a boolean",
+		QuestionMark: " ", Colon: ": ",
+	})
+	mustRegisterLocale("es", Locale{
+		TrueString: "s", FalseString: "n",
+		BoolAliases: map[string]bool{"si": true, "sí": true, "no": false},
+		ErrNotInt:   "el valor tiene que ser un número entero",
+		ErrNotFloat: "el valor tiene que ser un número decimal",
+		ErrNotBool:  "no representa un valor booleano",
+		QuestionMark: " ", Colon: ": ",
+	})
+	mustRegisterLocale("fr", Locale{
+		TrueString: "o", FalseString: "n",
+		BoolAliases: map[string]bool{"oui": true, "non": false},
+		ErrNotInt:   "la valeur doit être un entier",
+		ErrNotFloat: "la valeur doit être un nombre décimal",
+		ErrNotBool:  "ne représente pas un booléen",
+		QuestionMark: " ", Colon: " : ",
+	})
+	mustRegisterLocale("de", Locale{
+		TrueString: "j", FalseString: "n",
+		BoolAliases: map[string]bool{"ja": true, "nein": false},
+		ErrNotInt:   "der Wert muss eine Ganzzahl sein",
+		ErrNotFloat: "der Wert muss eine Gleitkommazahl sein",
+		ErrNotBool:  "stellt keinen Wahrheitswert dar",
+		QuestionMark: " ", Colon: ": ",
+	})
+	mustRegisterLocale("ja", Locale{
+		TrueString: "y", FalseString: "n",
+		BoolAliases: map[string]bool{"hai": true, "iie": false},
+		ErrNotInt:   "整数を入力してください",
+		ErrNotFloat: "小数を入力してください",
+		ErrNotBool:  "真偽値として認識できません",
+		QuestionMark: " ", Colon: ": ",
+	})
+
+	currentLocale = locales["en"]
+}
+
+func mustRegisterLocale(name string, l Locale) {
+	if err := RegisterLocale(name, l); err != nil {
+		panic(err.String())
+	}
+}
+
+// Returns an error if 'l.TrueString' and 'l.FalseString' do not resolve
+// to true and false (respectively) under 'l' itself, so a broken locale
+// is rejected here instead of panicking later from 'NewQuestion'.
+func validateLocale(l Locale) os.Error {
+	v, err := l.atob(l.TrueString)
+	if err != nil || !v {
+		return os.NewError(fmt.Sprintf(
+			"TrueString %q does not resolve to true", l.TrueString))
+	}
+
+	v, err = l.atob(l.FalseString)
+	if err != nil || v {
+		return os.NewError(fmt.Sprintf(
+			"FalseString %q does not resolve to false", l.FalseString))
+	}
+
+	return nil
+}
+
+// Registers 'l' under 'name' so it can later be selected with
+// 'NewQuestionLocale'.
+func RegisterLocale(name string, l Locale) os.Error {
+	if err := validateLocale(l); err != nil {
+		return os.NewError(fmt.Sprintf("locale %q: %s", name, err))
+	}
+
+	locales[name] = l
+	return nil
+}
+
+// Makes 'l' the locale used by every 'Question' created afterwards with
+// 'NewQuestion'.
+func SetLocale(l Locale) os.Error {
+	if err := validateLocale(l); err != nil {
+		return err
+	}
+
+	currentLocale = l
+	return nil
+}
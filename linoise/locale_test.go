@@ -0,0 +1,62 @@
+// Copyright 2010  The "go-linoise" Authors
+//
+// Use of this source code is governed by the Simplified BSD License
+// that can be found in the LICENSE file.
+//
+// This software is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES
+// OR CONDITIONS OF ANY KIND, either express or implied. See the License
+// for more details.
+
+package linoise
+
+import "testing"
+
+func TestRegisterLocaleRejectsBrokenLocale(t *testing.T) {
+	broken := Locale{TrueString: "y", FalseString: "y"} // both resolve to true
+	if err := RegisterLocale("broken", broken); err == nil {
+		t.Error("RegisterLocale with FalseString == TrueString = nil, want an error")
+	}
+	if _, ok := locales["broken"]; ok {
+		t.Error(`RegisterLocale left "broken" registered after rejecting it`)
+	}
+}
+
+func TestLocaleAtobCaseInsensitive(t *testing.T) {
+	es := locales["es"]
+
+	for _, str := range []string{"S", "s", "Sí", "SÍ", "si", "SI"} {
+		v, err := es.atob(str)
+		if err != nil || !v {
+			t.Errorf("es.atob(%q) = (%v, %s), want (true, nil)", str, v, err)
+		}
+	}
+
+	v, err := es.atob("NO")
+	if err != nil || v {
+		t.Errorf(`es.atob("NO") = (%v, %s), want (false, nil)`, v, err)
+	}
+
+	if _, err := es.atob("maybe"); err == nil {
+		t.Error(`es.atob("maybe") = nil error, want one`)
+	}
+}
+
+func TestSetLocale(t *testing.T) {
+	defer SetLocale(locales["en"])
+
+	fr := locales["fr"]
+	if err := SetLocale(fr); err != nil {
+		t.Fatalf("SetLocale(fr) = %s, want nil", err)
+	}
+	if currentLocale.TrueString != fr.TrueString {
+		t.Errorf("currentLocale.TrueString = %q, want %q", currentLocale.TrueString, fr.TrueString)
+	}
+
+	broken := Locale{TrueString: "y", FalseString: "y"}
+	if err := SetLocale(broken); err == nil {
+		t.Error("SetLocale with a broken locale = nil, want an error")
+	}
+	if currentLocale.TrueString != fr.TrueString {
+		t.Error("SetLocale with a broken locale changed currentLocale, want it left alone")
+	}
+}
@@ -0,0 +1,446 @@
+// Copyright 2010  The "go-linoise" Authors
+//
+// Use of this source code is governed by the Simplified BSD License
+// that can be found in the LICENSE file.
+//
+// This software is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES
+// OR CONDITIONS OF ANY KIND, either express or implied. See the License
+// for more details.
+
+package linoise
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kless/go-term/term"
+)
+
+
+// An Option configures a prompt created by Select, MultiSelect, Password
+// or Editor.
+type Option func(*promptConfig)
+
+type promptConfig struct {
+	pageSize int
+	mask     int
+}
+
+// PageSize sets how many choices 'Select' and 'MultiSelect' show at once
+// before paging. The default is 7.
+func PageSize(n int) Option {
+	return func(c *promptConfig) { c.pageSize = n }
+}
+
+// MaskRune sets the rune used by 'Password' to hide typed characters.
+// The default is '*'.
+func MaskRune(r int) Option {
+	return func(c *promptConfig) { c.mask = r }
+}
+
+func newPromptConfig(opts []Option) *promptConfig {
+	c := &promptConfig{pageSize: 7, mask: '*'}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+
+// === Select
+// ===
+
+// A Select asks the user to pick one choice from a list, navigated with
+// the arrow keys.
+type Select struct {
+	Choices []string
+}
+
+// Shows the prompt and returns the chosen value.
+func (s *Select) Ask(prompt string, opts ...Option) (string, os.Error) {
+	cfg := newPromptConfig(opts)
+	idx, err := chooseFromList(prompt, s.Choices, cfg, nil)
+	if err != nil {
+		return "", err
+	}
+	return s.Choices[idx[0]], nil
+}
+// ===
+
+
+// === MultiSelect
+// ===
+
+// A MultiSelect asks the user to pick any number of choices from a list,
+// toggled with Space and inverted with 'a'.
+type MultiSelect struct {
+	Choices []string
+}
+
+// Shows the prompt and returns the chosen values, in list order.
+func (s *MultiSelect) Ask(prompt string, opts ...Option) ([]string, os.Error) {
+	cfg := newPromptConfig(opts)
+	selected := make(map[int]bool)
+	idx, err := chooseFromList(prompt, s.Choices, cfg, selected)
+	if err != nil {
+		return nil, err
+	}
+
+	answer := make([]string, 0, len(idx))
+	for _, i := range idx {
+		answer = append(answer, s.Choices[i])
+	}
+	return answer, nil
+}
+// ===
+
+
+// Logical keys decoded by 'readKey' out of an escape sequence. Negative
+// so they never collide with a plain byte value.
+const (
+	keyUp = -(iota + 1)
+	keyDown
+	keyPageUp
+	keyPageDown
+)
+
+// Reads a single key, decoding the "ESC [ A/B" arrow and "ESC [ 5/6 ~"
+// page-up/page-down escape sequences into 'keyUp'/'keyDown'/
+// 'keyPageUp'/'keyPageDown'. Any other byte, including a lone ESC, is
+// returned as-is.
+func readKey() (int, os.Error) {
+	r, err := readRune()
+	if err != nil || r != 27 {
+		return r, err
+	}
+
+	r2, err := readRune()
+	if err != nil || r2 != '[' {
+		return 27, nil
+	}
+
+	r3, err := readRune()
+	if err != nil {
+		return 27, nil
+	}
+
+	switch r3 {
+	case 'A':
+		return keyUp, nil
+	case 'B':
+		return keyDown, nil
+	case '5':
+		readRune() // consume the trailing '~'
+		return keyPageUp, nil
+	case '6':
+		readRune() // consume the trailing '~'
+		return keyPageDown, nil
+	}
+	return 27, nil
+}
+
+// Renders 'choices', lets the user navigate with the arrow keys (plus
+// Ctrl-N/Ctrl-P) and page with Page Up/Page Down, filter with '/', and
+// either accept a single choice (Enter, when 'multi' is nil) or toggle
+// any number of them (Space) and invert the set ('a') before accepting.
+// It returns the indexes into 'choices' that were chosen, in list order.
+func chooseFromList(prompt string, choices []string, cfg *promptConfig, multi map[int]bool) ([]int, os.Error) {
+	if err := term.RawMode(); err != nil {
+		return nil, err
+	}
+	defer term.RestoreTerm()
+
+	cur := 0
+	top := 0
+	filter := ""
+	visible := choices
+
+	for {
+		visible = filterChoices(choices, filter)
+		if cur >= len(visible) {
+			cur = 0
+		}
+		top = pageTop(top, cur, len(visible), cfg.pageSize)
+
+		end := top + cfg.pageSize
+		if end > len(visible) {
+			end = len(visible)
+		}
+		renderList(prompt, choices, visible[top:end], cur-top, filter, multi)
+
+		r, err := readKey()
+		if err != nil {
+			return nil, err
+		}
+
+		switch r {
+		case '\r', '\n':
+			if len(visible) == 0 {
+				continue
+			}
+			if multi == nil {
+				return []int{indexOf(choices, visible[cur])}, nil
+			}
+			return selectedIndexes(choices, multi), nil
+		case ' ':
+			if multi != nil && len(visible) > 0 {
+				i := indexOf(choices, visible[cur])
+				multi[i] = !multi[i]
+			}
+		case 'a':
+			if multi != nil {
+				for i := range choices {
+					multi[i] = !multi[i]
+				}
+			}
+		case '/':
+			filter = readFilter()
+		case keyDown, 14: // Down / Ctrl-N
+			if cur < len(visible)-1 {
+				cur++
+			}
+		case keyUp, 16: // Up / Ctrl-P
+			if cur > 0 {
+				cur--
+			}
+		case keyPageDown:
+			cur += cfg.pageSize
+			if cur > len(visible)-1 {
+				cur = len(visible) - 1
+			}
+		case keyPageUp:
+			cur -= cfg.pageSize
+			if cur < 0 {
+				cur = 0
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Keeps 'cur' inside a 'pageSize'-tall window of a 'total'-long list,
+// scrolling 'top' the minimum amount necessary.
+func pageTop(top, cur, total, pageSize int) int {
+	if top > cur {
+		top = cur
+	}
+	if cur >= top+pageSize {
+		top = cur - pageSize + 1
+	}
+	if top > total-pageSize {
+		top = total - pageSize
+	}
+	if top < 0 {
+		top = 0
+	}
+	return top
+}
+
+func filterChoices(choices []string, filter string) []string {
+	if filter == "" {
+		return choices
+	}
+
+	out := make([]string, 0, len(choices))
+	for _, c := range choices {
+		if strings.Contains(c, filter) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func indexOf(choices []string, value string) int {
+	for i, c := range choices {
+		if c == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func selectedIndexes(choices []string, multi map[int]bool) []int {
+	idx := make([]int, 0, len(multi))
+	for i := range choices {
+		if multi[i] {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// Renders the 'page' window of 'visible' choices, marking the cursor at
+// 'cur' (an index into 'page'). Checkbox state is looked up against
+// 'choices' (the unfiltered list) so it survives narrowing 'visible'
+// with a filter.
+func renderList(prompt string, choices []string, page []string, cur int, filter string, multi map[int]bool) {
+	output.WriteString("\r" + QuestionPrefix + prompt + "\r\n")
+	for i, c := range page {
+		mark := "  "
+		if i == cur {
+			mark = "> "
+		}
+		if multi != nil {
+			box := "[ ]"
+			if multi[indexOf(choices, c)] {
+				box = "[x]"
+			}
+			fmt.Fprintf(output, "%s%s %s\r\n", mark, box, c)
+		} else {
+			fmt.Fprintf(output, "%s%s\r\n", mark, c)
+		}
+	}
+	if filter != "" {
+		fmt.Fprintf(output, "/%s\r\n", filter)
+	}
+}
+
+// Reads a substring filter, terminated by Return.
+func readFilter() string {
+	buf := make([]int, 0)
+	for {
+		r, err := readRune()
+		if err != nil || r == '\r' || r == '\n' {
+			return string(buf)
+		}
+		if r == 127 || r == 8 {
+			if len(buf) > 0 {
+				buf = buf[:len(buf)-1]
+			}
+			continue
+		}
+		buf = append(buf, r)
+	}
+	return string(buf)
+}
+
+
+// === Password
+// ===
+
+// A Password reads a line of input without echoing it, showing the mask
+// rune in its place.
+type Password struct{}
+
+// Shows the prompt and returns the typed value.
+func (p *Password) Ask(prompt string, opts ...Option) (string, os.Error) {
+	cfg := newPromptConfig(opts)
+
+	if err := term.RawMode(); err != nil {
+		return "", err
+	}
+	defer term.RestoreTerm()
+
+	output.WriteString(QuestionPrefix + prompt + ": ")
+	buf := make([]int, 0)
+
+	for {
+		r, err := readRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			output.WriteString("\r\n")
+			return string(buf), nil
+		case 4:
+			if len(buf) == 0 {
+				return "", ErrCtrlD
+			}
+		case 127, 8:
+			if len(buf) > 0 {
+				buf = buf[:len(buf)-1]
+				output.WriteString("\b \b")
+			}
+		default:
+			buf = append(buf, r)
+			output.WriteString(string(cfg.mask))
+		}
+	}
+	return string(buf), nil
+}
+// ===
+
+
+// === Editor
+// ===
+
+// An Editor opens the user's $EDITOR on a temporary file and returns its
+// final contents.
+type Editor struct {
+	// Initial contents of the file, shown before the editor is opened.
+	Default string
+}
+
+// Counter used, together with the process ID, to give every 'Editor'
+// temp file a name of its own.
+var editorTempSeq = 0
+
+// Returns a temp file path that is unique to this process and this call,
+// so two concurrent Editor prompts (even from unrelated processes) never
+// share a file, and the O_EXCL it is opened with refuses to follow a
+// pre-existing symlink planted at the path.
+func editorTempPath() string {
+	editorTempSeq++
+	return fmt.Sprintf("%s/linoise-editor-%d-%d", os.TempDir(), os.Getpid(), editorTempSeq)
+}
+
+// Shows the prompt, then spawns $EDITOR and returns what was saved.
+func (e *Editor) Ask(prompt string, opts ...Option) (string, os.Error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	f, err := os.OpenFile(editorTempPath(), os.O_CREATE|os.O_EXCL|os.O_RDWR, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if e.Default != "" {
+		f.WriteString(e.Default)
+	}
+
+	fmt.Fprintf(output, "%s%s\n", QuestionPrefix, prompt)
+
+	proc, err := os.StartProcess(editor, []string{editor, f.Name()}, &os.ProcAttr{
+		Files: []*os.File{os.Stdin, os.Stdout, os.Stderr},
+	})
+	if err != nil {
+		return "", err
+	}
+	if _, err = proc.Wait(0); err != nil {
+		return "", err
+	}
+
+	data := make([]byte, 1<<20)
+	f.Seek(0, 0)
+	n, err := f.Read(data)
+	if err != nil && err != os.EOF {
+		return "", err
+	}
+	return string(data[:n]), nil
+}
+// ===
+
+
+// === Confirm
+// ===
+
+// Confirm is an alias of Question's boolean prompt, kept so the new
+// prompt suite reads consistently as Select/MultiSelect/Password/Editor/
+// Confirm.
+type Confirm struct {
+	Default bool
+}
+
+// Shows the prompt and returns the confirmed boolean.
+func (c *Confirm) Ask(prompt string, opts ...Option) (bool, os.Error) {
+	q := NewQuestion()
+	return q.ReadBool(prompt, c.Default), nil
+}
+// ===
@@ -0,0 +1,73 @@
+// Copyright 2010  The "go-linoise" Authors
+//
+// Use of this source code is governed by the Simplified BSD License
+// that can be found in the LICENSE file.
+//
+// This software is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES
+// OR CONDITIONS OF ANY KIND, either express or implied. See the License
+// for more details.
+
+package linoise
+
+import "testing"
+
+var choices = []string{"apple", "banana", "cherry"}
+
+func TestFilterChoices(t *testing.T) {
+	if got := filterChoices(choices, ""); len(got) != 3 {
+		t.Errorf("empty filter: got %v, want all 3 choices", got)
+	}
+
+	got := filterChoices(choices, "an")
+	if len(got) != 1 || got[0] != "banana" {
+		t.Errorf(`filter "an": got %v, want ["banana"]`, got)
+	}
+
+	if got := filterChoices(choices, "xyz"); len(got) != 0 {
+		t.Errorf(`filter "xyz": got %v, want none`, got)
+	}
+}
+
+func TestIndexOf(t *testing.T) {
+	if i := indexOf(choices, "cherry"); i != 2 {
+		t.Errorf(`indexOf("cherry") = %d, want 2`, i)
+	}
+	if i := indexOf(choices, "missing"); i != -1 {
+		t.Errorf(`indexOf("missing") = %d, want -1`, i)
+	}
+}
+
+// Selection state is keyed by index into the unfiltered 'choices', so a
+// toggled item that a filter hides is still the one 'selectedIndexes'
+// reports. The checkbox rendering in 'renderList' is not checked here.
+func TestSelectedIndexesSurviveFiltering(t *testing.T) {
+	multi := map[int]bool{indexOf(choices, "cherry"): true}
+
+	visible := filterChoices(choices, "an") // narrows to ["banana"]
+	if indexOf(choices, visible[0]) == indexOf(choices, "cherry") {
+		t.Fatal("test setup: filter unexpectedly matched the toggled item")
+	}
+
+	got := selectedIndexes(choices, multi)
+	if len(got) != 1 || choices[got[0]] != "cherry" {
+		t.Errorf("selectedIndexes = %v, want [cherry]'s index", got)
+	}
+}
+
+func TestPageTop(t *testing.T) {
+	cases := []struct {
+		top, cur, total, pageSize, want int
+	}{
+		{0, 0, 10, 3, 0},   // cursor already inside the window
+		{0, 5, 10, 3, 3},   // cursor below the window: scroll down
+		{5, 1, 10, 3, 1},   // cursor above the window: scroll up
+		{0, 2, 3, 7, 0},    // list shorter than a page: pinned at 0
+	}
+
+	for _, c := range cases {
+		if got := pageTop(c.top, c.cur, c.total, c.pageSize); got != c.want {
+			t.Errorf("pageTop(%d, %d, %d, %d) = %d, want %d",
+				c.top, c.cur, c.total, c.pageSize, got, c.want)
+		}
+	}
+}
@@ -21,46 +21,58 @@ import (
 
 // Values by default
 var (
-	QuestionPrefix      = " + " // String placed before of questions
-	QuestionErrPrefix   = "  "  // String placed before of error messages
-	QuestionTrueString  = "y"   // String to represent 'true'
-	QuestionFalseString = "n"   // String to represent 'false'
+	QuestionPrefix    = " + " // String placed before of questions
+	QuestionErrPrefix = "  "  // String placed before of error messages
 
 	QuestionFloatFmt  byte = 'g' // Format for float numbers
 	QuestionFloatPrec int  = -1  // Precision for float numbers
 )
 
-// To pass strings in another languages.
-var ExtraBoolString = make(map[string]bool)
+// Path of the file where every 'Question' accumulates its answer
+// history, shared across every prompt in the program. Empty disables
+// persistent history (the default); it is still kept in memory.
+var QuestionHistoryFile = ""
 
 
 // === Type
 // ===
 
 type Question struct {
-	trueString, falseString string
+	locale  Locale
+	history *History
 }
 
 
-// Gets a question type.
+// Gets a question type using the locale set with 'SetLocale' (English by
+// default).
 func NewQuestion() *Question {
-	// === Check the strings that represent a boolean.
-	_, err := atob(QuestionTrueString)
-	if err != nil {
-		panic(fmt.Sprintf("the string %q does not represent a boolean 'true'",
-			QuestionTrueString))
-	}
+	return newQuestion(currentLocale)
+}
 
-	_, err = atob(QuestionFalseString)
-	if err != nil {
-		panic(fmt.Sprintf("the string %q does not represent a boolean 'false'",
-			QuestionFalseString))
+// Gets a question type using the locale registered under 'name'.
+func NewQuestionLocale(name string) (*Question, os.Error) {
+	l, ok := locales[name]
+	if !ok {
+		return nil, os.NewError(fmt.Sprintf("locale %q is not registered", name))
 	}
+	return newQuestion(l), nil
+}
+
+func newQuestion(l Locale) *Question {
+	var history *History
+	var err os.Error
 
-	return &Question{
-		strings.ToLower(QuestionTrueString),
-		strings.ToLower(QuestionFalseString),
+	if QuestionHistoryFile != "" {
+		history, err = NewFileHistory(QuestionHistoryFile, 500)
+		if err != nil {
+			panic(fmt.Sprintf("could not open history file %q: %s",
+				QuestionHistoryFile, err))
+		}
+	} else {
+		history = NewMemHistory(500)
 	}
+
+	return &Question{l, history}
 }
 
 // Restores terminal settings.
@@ -81,12 +93,12 @@ func (q *Question) getLine(prompt, defaultAnswer string, hasDefault bool) *Line
 
 	// Add spaces
 	if strings.HasSuffix(prompt, "?") {
-		prompt += " "
+		prompt += q.locale.QuestionMark
 	} else {
-		prompt += ": "
+		prompt += q.locale.Colon
 	}
 
-	return NewLinePrompt(prompt, nil) // No history.
+	return NewLinePrompt(prompt, q.history)
 }
 
 // Base to read strings.
@@ -117,30 +129,41 @@ func (q *Question) ReadStringDefault(prompt, defaultAnswer string) string {
 	return q._baseReadString(prompt, defaultAnswer, true)
 }
 
-// Prints the question until to get an integer number.
-func (q *Question) _baseReadInt(prompt string, defaultAnswer int, hasDefault bool) int {
-	line := q.getLine(prompt, strconv.Itoa(defaultAnswer), hasDefault)
+// Prints the question waiting until to press Return, completing the
+// answer against 'completer' when the user presses Tab.
+func (q *Question) ReadStringWithCompleter(prompt string, completer Completer) string {
+	line := NewLineWithCompleter(QuestionPrefix+prompt+q.locale.Colon, q.history, completer)
 
 	for {
-		input, err := line.Read()
-		if err == ErrCtrlD {
-			break
+		answer, err := line.Read()
+		if answer != "" || err == ErrCtrlD {
+			return answer
 		}
+	}
+	return ""
+}
 
-		if input == "" && hasDefault {
-			return defaultAnswer
+// Prints the question until to get an integer number.
+func (q *Question) _baseReadInt(prompt string, defaultAnswer int, hasDefault bool) int {
+	isInt := func(answer string) os.Error {
+		if _, err := strconv.Atoi(answer); err != nil {
+			return os.NewError(q.locale.ErrNotInt)
 		}
+		return nil
+	}
 
-		answer, err := strconv.Atoi(input)
-		if err != nil {
-			fmt.Fprintf(output, "%s%q: value has to be an integer\n",
-				QuestionErrPrefix, input)
-			continue
-		} else {
-			return answer
-		}
+	opts := []AskOption{WithValidator(isInt)}
+	if hasDefault {
+		opts = append(opts, WithDefault(strconv.Itoa(defaultAnswer)))
 	}
-	return 0
+
+	answer, err := q.Ask(prompt, opts...)
+	if err == ErrCtrlD {
+		return 0
+	}
+
+	v, _ := strconv.Atoi(answer)
+	return v
 }
 
 // Prints the question until to get an integer number.
@@ -156,32 +179,25 @@ func (q *Question) ReadIntDefault(prompt string, defaultAnswer int) int {
 
 // Prints the question until to get a float number.
 func (q *Question) _baseReadFloat(prompt string, defaultAnswer float, hasDefault bool) float {
-	line := q.getLine(
-		prompt,
-		strconv.Ftoa(defaultAnswer, QuestionFloatFmt, QuestionFloatPrec),
-		hasDefault,
-	)
-
-	for {
-		input, err := line.Read()
-		if err == ErrCtrlD {
-			break
+	isFloat := func(answer string) os.Error {
+		if _, err := strconv.Atof(answer); err != nil {
+			return os.NewError(q.locale.ErrNotFloat)
 		}
+		return nil
+	}
 
-		if input == "" && hasDefault {
-			return defaultAnswer
-		}
+	opts := []AskOption{WithValidator(isFloat)}
+	if hasDefault {
+		opts = append(opts, WithDefault(strconv.Ftoa(defaultAnswer, QuestionFloatFmt, QuestionFloatPrec)))
+	}
 
-		answer, err := strconv.Atof(input)
-		if err != nil {
-			fmt.Fprintf(output, "%s%q: value has to be a float\n",
-				QuestionErrPrefix, input)
-			continue
-		} else {
-			return answer
-		}
+	answer, err := q.Ask(prompt, opts...)
+	if err == ErrCtrlD {
+		return 0.0
 	}
-	return 0.0
+
+	v, _ := strconv.Atof(answer)
+	return v
 }
 
 // Prints the question until to get a float number.
@@ -200,61 +216,31 @@ func (q *Question) ReadBool(prompt string, defaultAnswer bool) bool {
 	var options string
 
 	if defaultAnswer {
-		options = fmt.Sprintf("%s/%s", strings.ToUpper(q.trueString), q.falseString)
+		options = fmt.Sprintf("%s/%s", strings.ToUpper(q.locale.TrueString), q.locale.FalseString)
 	} else {
-		options = fmt.Sprintf("%s/%s", q.trueString, strings.ToUpper(q.falseString))
+		options = fmt.Sprintf("%s/%s", q.locale.TrueString, strings.ToUpper(q.locale.FalseString))
 	}
 
-	line := q.getLine(prompt, options, true)
-
-	for {
-		input, err := line.Read()
-		if err == ErrCtrlD {
-			break
-		}
-
-		if input == "" {
-			return defaultAnswer
-		}
-
-		answer, err := atob(input)
-		if err != nil {
-			fmt.Fprintf(output, "%s%q: does not represent a boolean\n",
-				QuestionErrPrefix, input)
-			continue
-		} else {
-			return answer
+	isBool := func(answer string) os.Error {
+		if _, err := q.locale.atob(answer); err != nil {
+			return os.NewError(q.locale.ErrNotBool)
 		}
+		return nil
 	}
-	return false
-}
 
-
-// === Utility
-// ===
-
-// Returns the boolean value represented by the string.
-// It accepts "y, Y, yes, YES, Yes, n, N, no, NO, No". And values in
-// 'strconv.Atob', and 'ExtraBoolString'. Any other value returns an error.
-func atob(str string) (value bool, err os.Error) {
-	v, err := strconv.Atob(str)
-	if err == nil {
-		return v, nil
+	defaultAns := q.locale.FalseString
+	if defaultAnswer {
+		defaultAns = q.locale.TrueString
 	}
 
-	switch str {
-	case "y", "Y", "yes", "YES", "Yes":
-		return true, nil
-	case "n", "N", "no", "NO", "No":
-		return false, nil
-	}
+	line := q.getLine(prompt, options, true)
+	cfg := &askConfig{validator: isBool, defaultAns: defaultAns, hasDefault: true}
 
-	// Check extra characters, if any.
-	boolExtra, ok := ExtraBoolString[str]
-	if ok {
-		return boolExtra, nil
+	answer, err := q.askLine(line, cfg)
+	if err == ErrCtrlD {
+		return false
 	}
 
-	return false, os.NewError("wrong")
+	v, _ := q.locale.atob(answer)
+	return v
 }
-
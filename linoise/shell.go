@@ -0,0 +1,211 @@
+// Copyright 2010  The "go-linoise" Authors
+//
+// Use of this source code is governed by the Simplified BSD License
+// that can be found in the LICENSE file.
+//
+// This software is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES
+// OR CONDITIONS OF ANY KIND, either express or implied. See the License
+// for more details.
+
+package linoise
+
+import (
+	"fmt"
+	"os"
+)
+
+
+// Default prompt shown by a Shell.
+var ShellPrefix = "> "
+
+
+// === Type
+// ===
+
+// A Cmd is a command that a Shell can dispatch to, optionally carrying
+// its own subcommands.
+type Cmd struct {
+	Name string
+	Help string
+	Func func(c *Context, args []string) os.Error
+
+	subcommands []*Cmd
+}
+
+// Registers 'child' as a subcommand of 'c', reachable as "c.Name child.Name".
+func (c *Cmd) AddCmd(child *Cmd) {
+	c.subcommands = append(c.subcommands, child)
+}
+
+func (c *Cmd) find(name string) *Cmd {
+	for _, sub := range c.subcommands {
+		if sub.Name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+
+// A Context is passed to every Cmd.Func, giving it access to the shell
+// that is running it.
+type Context struct {
+	Shell *Shell
+}
+
+
+// A Shell turns 'Line.Read' into a REPL: it shows a prompt, tokenizes
+// the input and dispatches it to a registered Cmd, completing command
+// names with Tab and searching past input with Ctrl-R.
+type Shell struct {
+	Prompt  string
+	root    *Cmd
+	history *History
+}
+
+// Gets a shell with the built-in "help", "exit" and "history" commands
+// already registered.
+func NewShell() *Shell {
+	sh := &Shell{
+		Prompt:  ShellPrefix,
+		root:    &Cmd{},
+		history: NewMemHistory(500),
+	}
+
+	sh.AddCmd(&Cmd{
+		Name: "help",
+		Help: "show every available command",
+		Func: func(c *Context, args []string) os.Error {
+			for _, cmd := range c.Shell.root.subcommands {
+				fmt.Fprintf(output, "%-12s %s\n", cmd.Name, cmd.Help)
+			}
+			return nil
+		},
+	})
+	sh.AddCmd(&Cmd{
+		Name: "exit",
+		Help: "leave the shell",
+		Func: func(c *Context, args []string) os.Error {
+			return ErrCtrlD
+		},
+	})
+	sh.AddCmd(&Cmd{
+		Name: "history",
+		Help: "show previously run commands",
+		Func: func(c *Context, args []string) os.Error {
+			for _, line := range c.Shell.history.Entries() {
+				fmt.Fprintln(output, line)
+			}
+			return nil
+		},
+	})
+
+	return sh
+}
+// ===
+
+
+// Registers 'cmd' at the top level of the shell.
+func (sh *Shell) AddCmd(cmd *Cmd) {
+	sh.root.AddCmd(cmd)
+}
+
+// Runs the read-tokenize-dispatch loop until the user presses Ctrl-D, a
+// command returns 'ErrCtrlD', or input fails.
+func (sh *Shell) Run() os.Error {
+	completer := &PrefixCompleter{}
+	for _, cmd := range sh.root.subcommands {
+		completer.children = append(completer.children, cmdCompleter(cmd))
+	}
+
+	line := NewLineWithCompleter(sh.Prompt, sh.history, completer)
+
+	for {
+		input, err := line.Read()
+		if err == ErrCtrlD {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		args := tokenize(input)
+		if len(args) == 0 {
+			continue
+		}
+
+		cmd := sh.root.find(args[0])
+		if cmd == nil {
+			fmt.Fprintf(output, "%s: command not found\n", args[0])
+			continue
+		}
+
+		args = args[1:]
+		for len(args) > 0 {
+			sub := cmd.find(args[0])
+			if sub == nil {
+				break
+			}
+			cmd, args = sub, args[1:]
+		}
+
+		if err := cmd.Func(&Context{Shell: sh}, args); err != nil {
+			if err == ErrCtrlD {
+				return nil
+			}
+			fmt.Fprintf(output, "%s: %s\n", cmd.Name, err)
+		}
+	}
+	return nil
+}
+
+
+// Builds the Tab-completion trie for 'cmd' and, recursively, every
+// subcommand registered under it.
+func cmdCompleter(cmd *Cmd) *PrefixCompleter {
+	p := &PrefixCompleter{name: cmd.Name}
+	for _, sub := range cmd.subcommands {
+		p.children = append(p.children, cmdCompleter(sub))
+	}
+	return p
+}
+
+// Splits 'line' into fields, honouring single and double quotes as
+// flynn/go-shlex does, so a quoted argument may contain spaces or,
+// quoted empty ("" or ''), be the empty string.
+func tokenize(line string) []string {
+	var fields []string
+	var cur []int
+	var quote int
+	started := false
+
+	flush := func() {
+		if started {
+			fields = append(fields, string(cur))
+			cur = cur[:0]
+			started = false
+		}
+	}
+
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur = append(cur, r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			started = true
+		case r == ' ' || r == '\t':
+			flush()
+		default:
+			cur = append(cur, r)
+			started = true
+		}
+	}
+	flush()
+
+	return fields
+}
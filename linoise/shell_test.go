@@ -0,0 +1,88 @@
+// Copyright 2010  The "go-linoise" Authors
+//
+// Use of this source code is governed by the Simplified BSD License
+// that can be found in the LICENSE file.
+//
+// This software is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES
+// OR CONDITIONS OF ANY KIND, either express or implied. See the License
+// for more details.
+
+package linoise
+
+import "testing"
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		in   string
+		want []string
+	}{
+		{`connect host`, []string{"connect", "host"}},
+		{`connect "host name"`, []string{"connect", "host name"}},
+		{`connect 'host name'`, []string{"connect", "host name"}},
+		{`cmd ""`, []string{"cmd", ""}},
+		{`cmd '' arg`, []string{"cmd", "", "arg"}},
+		{``, nil},
+		{`   `, nil},
+	}
+
+	for _, c := range cases {
+		got := tokenize(c.in)
+		if len(got) != len(c.want) {
+			t.Errorf("tokenize(%q) = %#v, want %#v", c.in, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("tokenize(%q) = %#v, want %#v", c.in, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestCmdFindNested(t *testing.T) {
+	root := &Cmd{}
+	connect := &Cmd{Name: "connect"}
+	connect.AddCmd(&Cmd{Name: "host"})
+	root.AddCmd(connect)
+	root.AddCmd(&Cmd{Name: "disconnect"})
+
+	if root.find("connect") != connect {
+		t.Fatal("find(\"connect\") did not return the registered command")
+	}
+	if root.find("connect").find("host") == nil {
+		t.Error(`find("connect").find("host") = nil, want the nested "host" command`)
+	}
+	if root.find("missing") != nil {
+		t.Error(`find("missing") should be nil`)
+	}
+}
+
+func TestCmdCompleterIncludesSubcommands(t *testing.T) {
+	connect := &Cmd{Name: "connect"}
+	connect.AddCmd(&Cmd{Name: "host"})
+	connect.AddCmd(&Cmd{Name: "port"})
+
+	p := cmdCompleter(connect)
+	if p.name != "connect" {
+		t.Fatalf("cmdCompleter name = %q, want \"connect\"", p.name)
+	}
+	if len(p.children) != 2 {
+		t.Fatalf("cmdCompleter(connect).children = %v, want 2 entries", p.children)
+	}
+
+	// Completion starts from a nameless root, as in 'Shell.Run'.
+	root := &PrefixCompleter{children: []*PrefixCompleter{p}}
+	candidates, length := root.Do([]int("connect "), len("connect "))
+	if length != 0 {
+		t.Errorf("length = %d, want 0 after a trailing space", length)
+	}
+
+	names := make(map[string]bool)
+	for _, c := range candidates {
+		names[string(c)] = true
+	}
+	if !names["host"] || !names["port"] {
+		t.Errorf("candidates = %v, want both \"host\" and \"port\"", candidates)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2010  The "go-linoise" Authors
+//
+// Use of this source code is governed by the Simplified BSD License
+// that can be found in the LICENSE file.
+//
+// This software is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES
+// OR CONDITIONS OF ANY KIND, either express or implied. See the License
+// for more details.
+
+package linoise
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+)
+
+
+// A Validator checks an answer before it is accepted, returning a
+// descriptive error if it is not acceptable.
+type Validator func(answer string) os.Error
+
+// A Transformer rewrites an accepted answer before it is returned, e.g.
+// to trim whitespace or change case.
+type Transformer func(answer string) string
+
+
+// An AskOption configures a prompt started with 'Question.Ask'.
+type AskOption func(*askConfig)
+
+type askConfig struct {
+	validator   Validator
+	transformer Transformer
+	defaultAns  string
+	hasDefault  bool
+}
+
+// WithValidator makes 'Ask' re-prompt, showing 'v's error, until 'v'
+// accepts the answer.
+func WithValidator(v Validator) AskOption {
+	return func(c *askConfig) {
+		if c.validator != nil {
+			c.validator = ComposeValidators(c.validator, v)
+		} else {
+			c.validator = v
+		}
+	}
+}
+
+// WithTransform makes 'Ask' run 't' over the answer before returning it.
+func WithTransform(t Transformer) AskOption {
+	return func(c *askConfig) { c.transformer = t }
+}
+
+// WithDefault makes 'Ask' return 'value' when the user presses Return on
+// an empty line.
+func WithDefault(value string) AskOption {
+	return func(c *askConfig) { c.defaultAns = value; c.hasDefault = true }
+}
+
+
+// Prints the question until the answer passes every validator, then
+// runs the transformer (if any) and returns the result. This is the
+// generic path that 'ReadInt', 'ReadFloat' and 'ReadBool' are built on.
+func (q *Question) Ask(prompt string, opts ...AskOption) (string, os.Error) {
+	cfg := &askConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	line := q.getLine(prompt, cfg.defaultAns, cfg.hasDefault)
+	return q.askLine(line, cfg)
+}
+
+// Applies 'cfg' to a read 'answer': an empty answer is replaced by the
+// default without running the validator, otherwise the validator (if
+// any) must accept it before the transformer (if any) runs. 'reject'
+// tells 'askLine' to show 'verr' and re-prompt instead of returning.
+func (cfg *askConfig) resolve(answer string) (result string, reject bool, verr os.Error) {
+	if answer == "" && cfg.hasDefault {
+		return cfg.defaultAns, false, nil
+	}
+
+	if cfg.validator != nil {
+		if err := cfg.validator(answer); err != nil {
+			return "", true, err
+		}
+	}
+
+	if cfg.transformer != nil {
+		answer = cfg.transformer(answer)
+	}
+	return answer, false, nil
+}
+
+// Runs the read-validate-transform loop of 'Ask' over an already built
+// 'line', so callers that need a prompt whose displayed default (e.g.
+// "[Y/n]") differs from the value substituted on an empty answer can
+// build the line themselves.
+func (q *Question) askLine(line *Line, cfg *askConfig) (string, os.Error) {
+	for {
+		answer, err := line.Read()
+		if err == ErrCtrlD {
+			return "", err
+		}
+
+		result, reject, verr := cfg.resolve(answer)
+		if reject {
+			fmt.Fprintf(output, "%s%s\n", QuestionErrPrefix, verr)
+			continue
+		}
+		return result, nil
+	}
+	return "", nil
+}
+
+// Prints the question until 'v' accepts the answer.
+func (q *Question) ReadStringValidated(prompt string, v Validator) string {
+	answer, err := q.Ask(prompt, WithValidator(v))
+	if err != nil {
+		return ""
+	}
+	return answer
+}
+
+
+// === Built-in validators
+// ===
+
+// Required rejects the empty answer.
+func Required(answer string) os.Error {
+	if answer == "" {
+		return os.NewError("a value is required")
+	}
+	return nil
+}
+
+// MinLength rejects answers shorter than 'n' runes.
+func MinLength(n int) Validator {
+	return func(answer string) os.Error {
+		if len([]int(answer)) < n {
+			return os.NewError(fmt.Sprintf("must be at least %d characters", n))
+		}
+		return nil
+	}
+}
+
+// MaxLength rejects answers longer than 'n' runes.
+func MaxLength(n int) Validator {
+	return func(answer string) os.Error {
+		if len([]int(answer)) > n {
+			return os.NewError(fmt.Sprintf("must be at most %d characters", n))
+		}
+		return nil
+	}
+}
+
+// Regexp rejects answers that do not match 'pattern'.
+func Regexp(pattern string) Validator {
+	re := regexp.MustCompile(pattern)
+	return func(answer string) os.Error {
+		if !re.MatchString(answer) {
+			return os.NewError(fmt.Sprintf("must match %q", pattern))
+		}
+		return nil
+	}
+}
+
+// IntRange rejects answers that are not an integer between 'lo' and
+// 'hi', inclusive.
+func IntRange(lo, hi int) Validator {
+	return func(answer string) os.Error {
+		v, err := strconv.Atoi(answer)
+		if err != nil {
+			return os.NewError("value has to be an integer")
+		}
+		if v < lo || v > hi {
+			return os.NewError(fmt.Sprintf("must be between %d and %d", lo, hi))
+		}
+		return nil
+	}
+}
+
+// FloatRange rejects answers that are not a float between 'lo' and
+// 'hi', inclusive.
+func FloatRange(lo, hi float) Validator {
+	return func(answer string) os.Error {
+		v, err := strconv.Atof(answer)
+		if err != nil {
+			return os.NewError("value has to be a float")
+		}
+		if v < lo || v > hi {
+			return os.NewError(fmt.Sprintf("must be between %g and %g", lo, hi))
+		}
+		return nil
+	}
+}
+
+// ComposeValidators runs every validator in order, stopping at (and
+// returning) the first error.
+func ComposeValidators(validators ...Validator) Validator {
+	return func(answer string) os.Error {
+		for _, v := range validators {
+			if err := v(answer); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+// ===
@@ -0,0 +1,142 @@
+// Copyright 2010  The "go-linoise" Authors
+//
+// Use of this source code is governed by the Simplified BSD License
+// that can be found in the LICENSE file.
+//
+// This software is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES
+// OR CONDITIONS OF ANY KIND, either express or implied. See the License
+// for more details.
+
+package linoise
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRequired(t *testing.T) {
+	if err := Required(""); err == nil {
+		t.Error(`Required("") = nil, want an error`)
+	}
+	if err := Required("x"); err != nil {
+		t.Errorf(`Required("x") = %s, want nil`, err)
+	}
+}
+
+func TestMinLength(t *testing.T) {
+	v := MinLength(3)
+	if err := v("ab"); err == nil {
+		t.Error(`MinLength(3)("ab") = nil, want an error`)
+	}
+	if err := v("abc"); err != nil {
+		t.Errorf(`MinLength(3)("abc") = %s, want nil`, err)
+	}
+	// "café" has 4 runes but 5 bytes; the count must be by rune.
+	if err := v("café"); err != nil {
+		t.Errorf(`MinLength(3)("café") = %s, want nil`, err)
+	}
+}
+
+func TestMaxLength(t *testing.T) {
+	v := MaxLength(4)
+	if err := v("abcde"); err == nil {
+		t.Error(`MaxLength(4)("abcde") = nil, want an error`)
+	}
+	// "café" has 4 runes but 5 bytes; the count must be by rune.
+	if err := v("café"); err != nil {
+		t.Errorf(`MaxLength(4)("café") = %s, want nil`, err)
+	}
+}
+
+func TestRegexp(t *testing.T) {
+	v := Regexp("^[0-9]+$")
+	if err := v("abc"); err == nil {
+		t.Error(`Regexp("^[0-9]+$")("abc") = nil, want an error`)
+	}
+	if err := v("123"); err != nil {
+		t.Errorf(`Regexp("^[0-9]+$")("123") = %s, want nil`, err)
+	}
+}
+
+func TestIntRange(t *testing.T) {
+	v := IntRange(1, 10)
+	if err := v("abc"); err == nil {
+		t.Error(`IntRange(1, 10)("abc") = nil, want an error`)
+	}
+	if err := v("0"); err == nil {
+		t.Error(`IntRange(1, 10)("0") = nil, want an error`)
+	}
+	if err := v("11"); err == nil {
+		t.Error(`IntRange(1, 10)("11") = nil, want an error`)
+	}
+	if err := v("5"); err != nil {
+		t.Errorf(`IntRange(1, 10)("5") = %s, want nil`, err)
+	}
+}
+
+func TestFloatRange(t *testing.T) {
+	v := FloatRange(0.0, 1.0)
+	if err := v("abc"); err == nil {
+		t.Error(`FloatRange(0, 1)("abc") = nil, want an error`)
+	}
+	if err := v("1.5"); err == nil {
+		t.Error(`FloatRange(0, 1)("1.5") = nil, want an error`)
+	}
+	if err := v("0.5"); err != nil {
+		t.Errorf(`FloatRange(0, 1)("0.5") = %s, want nil`, err)
+	}
+}
+
+func TestComposeValidators(t *testing.T) {
+	v := ComposeValidators(Required, MinLength(3))
+	if err := v(""); err == nil {
+		t.Error(`ComposeValidators(Required, MinLength(3))("") = nil, want an error`)
+	}
+	if err := v("ab"); err == nil {
+		t.Error(`ComposeValidators(Required, MinLength(3))("ab") = nil, want an error`)
+	}
+	if err := v("abc"); err != nil {
+		t.Errorf(`ComposeValidators(Required, MinLength(3))("abc") = %s, want nil`, err)
+	}
+}
+
+func TestAskConfigResolveDefault(t *testing.T) {
+	called := false
+	cfg := &askConfig{
+		validator:  func(string) os.Error { called = true; return nil },
+		defaultAns: "fallback",
+		hasDefault: true,
+	}
+
+	result, reject, err := cfg.resolve("")
+	if reject || err != nil {
+		t.Fatalf(`resolve("") = (%q, %v, %s), want no rejection`, result, reject, err)
+	}
+	if result != "fallback" {
+		t.Errorf(`resolve("") = %q, want "fallback"`, result)
+	}
+	if called {
+		t.Error("resolve(\"\") ran the validator, want it skipped for a defaulted answer")
+	}
+}
+
+func TestAskConfigResolveValidatorRejects(t *testing.T) {
+	cfg := &askConfig{validator: Required}
+
+	result, reject, err := cfg.resolve("")
+	if !reject || err == nil {
+		t.Fatalf(`resolve("") = (%q, %v, %s), want a rejection`, result, reject, err)
+	}
+}
+
+func TestAskConfigResolveTransform(t *testing.T) {
+	cfg := &askConfig{transformer: func(s string) string { return s + "!" }}
+
+	result, reject, err := cfg.resolve("hi")
+	if reject || err != nil {
+		t.Fatalf(`resolve("hi") = (%q, %v, %s), want no rejection`, result, reject, err)
+	}
+	if result != "hi!" {
+		t.Errorf(`resolve("hi") = %q, want "hi!"`, result)
+	}
+}